@@ -0,0 +1,129 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// cachedStats is a snapshot of a target's /stats response together with
+// when it was taken.
+type cachedStats struct {
+	stats     *ServerMetricsResponse
+	scrapedAt time.Time
+}
+
+// targetCache polls a single target's /stats endpoint on a fixed interval
+// in the background and serves the most recent successful snapshot. This
+// decouples the backend scrape from Prometheus's own scrape interval, so a
+// slow or rate-limited backend isn't hit once per Prometheus replica.
+type targetCache struct {
+	snapshot atomic.Pointer[cachedStats]
+	stop     chan struct{}
+}
+
+func (c *targetCache) get() (*cachedStats, bool) {
+	s := c.snapshot.Load()
+	return s, s != nil
+}
+
+func (c *targetCache) run(client *http.Client, serverURL string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	c.refresh(client, serverURL)
+	for {
+		select {
+		case <-ticker.C:
+			c.refresh(client, serverURL)
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *targetCache) refresh(client *http.Client, serverURL string) {
+	stats, err := fetchStats(context.Background(), client, serverURL)
+	if err != nil {
+		slog.Warn("background scrape refresh failed, serving stale cache", "target", serverURL, "err", err)
+		return
+	}
+
+	c.snapshot.Store(&cachedStats{stats: stats, scrapedAt: time.Now()})
+}
+
+var (
+	cachesMu sync.Mutex
+	caches   = map[string]*targetCache{}
+)
+
+// getOrStartCache returns the background cache for serverURL, starting its
+// polling goroutine the first time it's requested for that target. The
+// client and interval passed on that first call stick until the cache is
+// evicted by ReconcileCaches; a config reload that changes a target's auth
+// or timeout without changing its URL only takes effect once the process
+// restarts.
+func getOrStartCache(client *http.Client, serverURL string, interval time.Duration) *targetCache {
+	cachesMu.Lock()
+	defer cachesMu.Unlock()
+
+	if c, ok := caches[serverURL]; ok {
+		return c
+	}
+
+	c := &targetCache{stop: make(chan struct{})}
+	caches[serverURL] = c
+	go c.run(client, serverURL, interval)
+	return c
+}
+
+// ReconcileCaches stops and evicts the background poller for any cached
+// serverURL not present in active, so a config reload that removes or
+// renames a target doesn't leave its poller running, and scraping its old
+// backend, forever.
+func ReconcileCaches(active map[string]struct{}) {
+	cachesMu.Lock()
+	defer cachesMu.Unlock()
+
+	for serverURL, c := range caches {
+		if _, ok := active[serverURL]; !ok {
+			close(c.stop)
+			delete(caches, serverURL)
+		}
+	}
+}
+
+// lookupCache returns serverURL's cache without starting one, for reporting
+// freshness metrics alongside whichever collector actually reads it.
+func lookupCache(serverURL string) (*cachedStats, bool) {
+	cachesMu.Lock()
+	c, ok := caches[serverURL]
+	cachesMu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return c.get()
+}
+
+// fetchCachedStats returns the most recent ServerMetricsResponse for
+// serverURL. When cacheInterval is zero, caching is disabled and it scrapes
+// /stats directly; otherwise it starts (if needed) a background poller for
+// serverURL and serves its latest snapshot, falling back to a synchronous
+// scrape if no snapshot exists yet (e.g. right after startup).
+func fetchCachedStats(ctx context.Context, client *http.Client, serverURL string, cacheInterval time.Duration) (*ServerMetricsResponse, error) {
+	if cacheInterval <= 0 {
+		return fetchStats(ctx, client, serverURL)
+	}
+
+	cache := getOrStartCache(client, serverURL, cacheInterval)
+	if snap, ok := cache.get(); ok {
+		return snap.stats, nil
+	}
+
+	return fetchStats(ctx, client, serverURL)
+}
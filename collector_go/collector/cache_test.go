@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statsServer returns an httptest.Server serving /stats with a counter that
+// increments on every request, so tests can tell how many times the
+// backend was actually scraped.
+func statsServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		json.NewEncoder(w).Encode(ServerMetricsResponse{})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestFetchCachedStatsDisabledFetchesEveryCall(t *testing.T) {
+	srv, hits := statsServer(t)
+
+	for i := 0; i < 3; i++ {
+		if _, err := fetchCachedStats(context.Background(), srv.Client(), srv.URL, 0); err != nil {
+			t.Fatalf("fetchCachedStats: %s", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(hits); got != 3 {
+		t.Errorf("backend hit %d times, want 3 (no caching)", got)
+	}
+}
+
+// waitForCache polls until url's background cache has a snapshot, since
+// getOrStartCache's first refresh happens in a goroutine it doesn't wait on.
+func waitForCache(t *testing.T, url string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := lookupCache(url); ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("cache for %s never populated", url)
+}
+
+func TestFetchCachedStatsHitsBackgroundCache(t *testing.T) {
+	srv, hits := statsServer(t)
+	t.Cleanup(func() { ReconcileCaches(map[string]struct{}{}) })
+
+	// Starts the poller; it may race a synchronous fallback fetch before
+	// its first refresh lands, so only assert once it has settled.
+	if _, err := fetchCachedStats(context.Background(), srv.Client(), srv.URL, time.Hour); err != nil {
+		t.Fatalf("fetchCachedStats (starts poller): %s", err)
+	}
+	waitForCache(t, srv.URL)
+
+	before := atomic.LoadInt32(hits)
+	for i := 0; i < 3; i++ {
+		if _, err := fetchCachedStats(context.Background(), srv.Client(), srv.URL, time.Hour); err != nil {
+			t.Fatalf("fetchCachedStats: %s", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(hits); got != before {
+		t.Errorf("backend hit %d more times, want 0 (subsequent calls should read the cached snapshot)", got-before)
+	}
+}
+
+func TestReconcileCachesStopsDroppedTargets(t *testing.T) {
+	srv, hits := statsServer(t)
+
+	if _, err := fetchCachedStats(context.Background(), srv.Client(), srv.URL, time.Hour); err != nil {
+		t.Fatalf("fetchCachedStats (starts poller): %s", err)
+	}
+	waitForCache(t, srv.URL)
+
+	ReconcileCaches(map[string]struct{}{})
+
+	if _, ok := lookupCache(srv.URL); ok {
+		t.Fatal("lookupCache after ReconcileCaches dropped the target = true, want false")
+	}
+
+	hitsAfterStop := atomic.LoadInt32(hits)
+	time.Sleep(20 * time.Millisecond)
+	if got := atomic.LoadInt32(hits); got != hitsAfterStop {
+		t.Errorf("backend still being hit after ReconcileCaches stopped its poller: %d -> %d", hitsAfterStop, got)
+	}
+}
@@ -0,0 +1,260 @@
+// Package collector implements a pluggable collector framework modelled on
+// node_exporter: each subsystem (cpu, memory, health, ...) registers a
+// Factory via init(), and NodeCollector composes whichever of them are
+// enabled into a single prometheus.Collector.
+package collector
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Config carries the per-target settings every collector factory needs to
+// reach the backend being scraped. Client and Context are expected to
+// already carry the target's timeout, TLS and auth settings, and to be
+// derived from the scrape request that's driving this collection, so a
+// cancelled scrape aborts in-flight backend requests too.
+type Config struct {
+	Namespace string
+	ServerURL string
+	Client    *http.Client
+	Context   context.Context
+
+	// CacheInterval, if greater than zero, tells collectors that read
+	// /stats to serve it from a background cache refreshed on this
+	// interval instead of scraping the backend on every collection.
+	CacheInterval time.Duration
+
+	// stats memoizes /stats across the collectors sharing this Config, so
+	// a scrape that enables both cpu and memory hits the backend (or the
+	// background cache) once instead of twice. Set by NewNodeCollector.
+	stats *statsMemo
+}
+
+// statsMemo fetches /stats at most once for however many collectors share
+// it, via sync.Once.
+type statsMemo struct {
+	once  sync.Once
+	stats *ServerMetricsResponse
+	err   error
+}
+
+// fetchStats returns cfg.ServerURL's /stats snapshot, live or cached per
+// cfg.CacheInterval. The first collector to call this for a given Config
+// does the actual fetch; every other collector sharing that Config this
+// scrape gets the same result.
+func (cfg Config) fetchStats(ctx context.Context) (*ServerMetricsResponse, error) {
+	if cfg.stats == nil {
+		return fetchCachedStats(ctx, cfg.httpClient(), cfg.ServerURL, cfg.CacheInterval)
+	}
+
+	cfg.stats.once.Do(func() {
+		cfg.stats.stats, cfg.stats.err = fetchCachedStats(ctx, cfg.httpClient(), cfg.ServerURL, cfg.CacheInterval)
+	})
+	return cfg.stats.stats, cfg.stats.err
+}
+
+// context returns cfg.Context, defaulting to context.Background() when the
+// caller didn't set one.
+func (cfg Config) context() context.Context {
+	if cfg.Context == nil {
+		return context.Background()
+	}
+	return cfg.Context
+}
+
+// httpClient returns cfg.Client, defaulting to http.DefaultClient when the
+// caller didn't set one.
+func (cfg Config) httpClient() *http.Client {
+	if cfg.Client == nil {
+		return http.DefaultClient
+	}
+	return cfg.Client
+}
+
+// Collector is implemented by every subsystem collector. Update fetches
+// fresh data and sends it on ch; a returned error marks the collector
+// unsuccessful for this scrape without preventing the others from running.
+type Collector interface {
+	Update(ch chan<- prometheus.Metric) error
+	Name() string
+}
+
+// Factory builds a Collector from a Config.
+type Factory func(cfg Config) (Collector, error)
+
+var (
+	factories         = map[string]Factory{}
+	enabledCollectors = map[string]*bool{}
+)
+
+// registerCollector is called from the init() of each sub-collector file to
+// add it to factories and wire up its --collector.<name> /
+// --no-collector.<name> flags.
+func registerCollector(name string, isDefaultEnabled bool, factory Factory) {
+	factories[name] = factory
+
+	enabled := new(bool)
+	*enabled = isDefaultEnabled
+	enabledCollectors[name] = enabled
+
+	flag.BoolVar(enabled, "collector."+name, isDefaultEnabled, fmt.Sprintf("Enable the %s collector", name))
+	flag.Var(&negatedBoolFlag{target: enabled}, "no-collector."+name, fmt.Sprintf("Disable the %s collector", name))
+}
+
+// negatedBoolFlag implements flag.Value so "--no-collector.<name>" behaves
+// as a bare switch that disables a collector. It shares its bool with
+// "--collector.<name>", so when both are passed, whichever comes last wins.
+type negatedBoolFlag struct {
+	target *bool
+}
+
+func (f *negatedBoolFlag) String() string { return "" }
+
+func (f *negatedBoolFlag) Set(v string) error {
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	*f.target = !enabled
+	return nil
+}
+
+func (f *negatedBoolFlag) IsBoolFlag() bool { return true }
+
+// NodeCollector composes the enabled sub-collectors. Besides each
+// collector's own metrics it reports, per collector, how long it took and
+// whether it succeeded, plus an overall up/scrape_duration_seconds pair so
+// a single failing collector doesn't hide that the target scrape as a
+// whole had a problem.
+type NodeCollector struct {
+	collectors            map[string]Collector
+	serverURL             string
+	cacheInterval         time.Duration
+	upDesc                *prometheus.Desc
+	scrapeDurationDesc    *prometheus.Desc
+	collectorDurationDesc *prometheus.Desc
+	collectorSuccessDesc  *prometheus.Desc
+	lastScrapeDesc        *prometheus.Desc
+	cacheStalenessDesc    *prometheus.Desc
+}
+
+// NewNodeCollector builds the enabled collectors for cfg. Disabled
+// collectors (via --no-collector.<name>) are skipped entirely. All of them
+// share cfg's statsMemo, so they fetch /stats at most once per scrape.
+func NewNodeCollector(cfg Config) (*NodeCollector, error) {
+	cfg.stats = &statsMemo{}
+
+	collectors := make(map[string]Collector, len(factories))
+	for name, factory := range factories {
+		if enabled := enabledCollectors[name]; enabled == nil || !*enabled {
+			continue
+		}
+
+		c, err := factory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create collector %s: %w", name, err)
+		}
+		collectors[name] = c
+	}
+
+	return &NodeCollector{
+		collectors:    collectors,
+		serverURL:     cfg.ServerURL,
+		cacheInterval: cfg.CacheInterval,
+		upDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "", "up"),
+			"Whether the target scrape succeeded (1) or failed (0)",
+			nil, nil,
+		),
+		scrapeDurationDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "", "scrape_duration_seconds"),
+			"Total time taken scraping the target across all collectors",
+			nil, nil,
+		),
+		collectorDurationDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "scrape_collector", "duration_seconds"),
+			"Duration of a collector's Update call",
+			[]string{"collector"}, nil,
+		),
+		collectorSuccessDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "scrape_collector", "success"),
+			"Whether a collector's last Update call succeeded",
+			[]string{"collector"}, nil,
+		),
+		lastScrapeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "", "last_scrape_timestamp_seconds"),
+			"Unix timestamp of the last successful background cache refresh",
+			nil, nil,
+		),
+		cacheStalenessDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "", "cache_staleness_seconds"),
+			"Age of the cached backend snapshot being served",
+			nil, nil,
+		),
+	}, nil
+}
+
+func (n *NodeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- n.upDesc
+	ch <- n.scrapeDurationDesc
+	ch <- n.collectorDurationDesc
+	ch <- n.collectorSuccessDesc
+	ch <- n.lastScrapeDesc
+	ch <- n.cacheStalenessDesc
+}
+
+func (n *NodeCollector) Collect(ch chan<- prometheus.Metric) {
+	start := time.Now()
+
+	var failures int32
+	var wg sync.WaitGroup
+	wg.Add(len(n.collectors))
+	for name, c := range n.collectors {
+		go func(name string, c Collector) {
+			defer wg.Done()
+			if err := n.execute(name, c, ch); err != nil {
+				atomic.AddInt32(&failures, 1)
+			}
+		}(name, c)
+	}
+	wg.Wait()
+
+	up := 1.0
+	if atomic.LoadInt32(&failures) > 0 {
+		up = 0.0
+	}
+
+	ch <- prometheus.MustNewConstMetric(n.upDesc, prometheus.GaugeValue, up)
+	ch <- prometheus.MustNewConstMetric(n.scrapeDurationDesc, prometheus.GaugeValue, time.Since(start).Seconds())
+
+	if n.cacheInterval > 0 {
+		if snap, ok := lookupCache(n.serverURL); ok {
+			ch <- prometheus.MustNewConstMetric(n.lastScrapeDesc, prometheus.GaugeValue, float64(snap.scrapedAt.Unix()))
+			ch <- prometheus.MustNewConstMetric(n.cacheStalenessDesc, prometheus.GaugeValue, time.Since(snap.scrapedAt).Seconds())
+		}
+	}
+}
+
+func (n *NodeCollector) execute(name string, c Collector, ch chan<- prometheus.Metric) error {
+	start := time.Now()
+	err := c.Update(ch)
+	duration := time.Since(start).Seconds()
+
+	success := 1.0
+	if err != nil {
+		success = 0.0
+	}
+
+	ch <- prometheus.MustNewConstMetric(n.collectorDurationDesc, prometheus.GaugeValue, duration, name)
+	ch <- prometheus.MustNewConstMetric(n.collectorSuccessDesc, prometheus.GaugeValue, success, name)
+	return err
+}
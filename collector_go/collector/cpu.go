@@ -0,0 +1,41 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	registerCollector("cpu", true, NewCPUCollector)
+}
+
+type cpuCollector struct {
+	cfg  Config
+	load *prometheus.Desc
+}
+
+// NewCPUCollector returns a Collector that reports the backend's CPU load
+// averages, scraped from its /stats endpoint.
+func NewCPUCollector(cfg Config) (Collector, error) {
+	return &cpuCollector{
+		cfg: cfg,
+		load: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "", "cpu_load"),
+			"CPU load average with 1m, 5m, and 15m labels",
+			[]string{"bucket"}, nil,
+		),
+	}, nil
+}
+
+func (c *cpuCollector) Name() string { return "cpu" }
+
+func (c *cpuCollector) Update(ch chan<- prometheus.Metric) error {
+	stats, err := c.cfg.fetchStats(c.cfg.context())
+	if err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.load, prometheus.GaugeValue, stats.CPU.Load1m, "1m")
+	ch <- prometheus.MustNewConstMetric(c.load, prometheus.GaugeValue, stats.CPU.Load5m, "5m")
+	ch <- prometheus.MustNewConstMetric(c.load, prometheus.GaugeValue, stats.CPU.Load15m, "15m")
+	return nil
+}
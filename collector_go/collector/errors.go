@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// scrapeErrorsTotal counts failed backend scrapes by the stage that failed,
+// so operators can alert on e.g. a spike in http_status failures without
+// digging through logs. It is a process-wide self-metric, not tied to any
+// one target, so it registers itself on the default registry rather than
+// the per-probe one.
+var scrapeErrorsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "scrape_errors_total",
+		Help: "number of backend scrape failures, by failing stage",
+	},
+	[]string{"stage"},
+)
+
+// scrapeFailure records a failed scrape stage: it increments
+// scrape_errors_total{stage=...} and logs a structured error carrying the
+// target, endpoint, and status code involved, then returns err unchanged so
+// callers can `return nil, scrapeFailure(...)` in one line.
+func scrapeFailure(stage, target, endpoint string, statusCode int, err error) error {
+	scrapeErrorsTotal.WithLabelValues(stage).Inc()
+	slog.Error("backend scrape failed",
+		"stage", stage,
+		"target", target,
+		"endpoint", endpoint,
+		"status_code", statusCode,
+		"err", err,
+	)
+	return err
+}
@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	registerCollector("health", true, NewHealthCollector)
+}
+
+type healthCollector struct {
+	serverURL string
+	client    *http.Client
+	ctx       context.Context
+	health    *prometheus.Desc
+}
+
+// NewHealthCollector returns a Collector that reports the backend's
+// /healthz status as a 0/1 gauge.
+func NewHealthCollector(cfg Config) (Collector, error) {
+	return &healthCollector{
+		serverURL: cfg.ServerURL,
+		client:    cfg.httpClient(),
+		ctx:       cfg.context(),
+		health: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "", "health"),
+			"health of the server",
+			nil, nil,
+		),
+	}, nil
+}
+
+func (c *healthCollector) Name() string { return "health" }
+
+func (c *healthCollector) Update(ch chan<- prometheus.Metric) error {
+	const endpoint = "/healthz"
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.serverURL+endpoint, nil)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.health, prometheus.GaugeValue, 0)
+		return scrapeFailure("request_build", c.serverURL, endpoint, 0, fmt.Errorf("could not build health request: %w", err))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.health, prometheus.GaugeValue, 0)
+		return scrapeFailure("transport", c.serverURL, endpoint, 0, fmt.Errorf("could not scrape health: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		ch <- prometheus.MustNewConstMetric(c.health, prometheus.GaugeValue, 0)
+		return scrapeFailure("http_status", c.serverURL, endpoint, resp.StatusCode, fmt.Errorf("unexpected status from health endpoint: %s", resp.Status))
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.health, prometheus.GaugeValue, 1)
+	return nil
+}
@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	registerCollector("memory", true, NewMemoryCollector)
+}
+
+type memoryCollector struct {
+	cfg         Config
+	memoryTotal *prometheus.Desc
+	memoryUsed  *prometheus.Desc
+}
+
+// NewMemoryCollector returns a Collector that reports the backend's memory
+// usage, scraped from its /stats endpoint.
+func NewMemoryCollector(cfg Config) (Collector, error) {
+	return &memoryCollector{
+		cfg: cfg,
+		memoryTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "", "memory_bytes_total"),
+			"total memory in bytes",
+			nil, nil,
+		),
+		memoryUsed: prometheus.NewDesc(
+			prometheus.BuildFQName(cfg.Namespace, "", "memory_bytes_used"),
+			"memory usage in bytes",
+			nil, nil,
+		),
+	}, nil
+}
+
+func (c *memoryCollector) Name() string { return "memory" }
+
+func (c *memoryCollector) Update(ch chan<- prometheus.Metric) error {
+	stats, err := c.cfg.fetchStats(c.cfg.context())
+	if err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.memoryTotal, prometheus.GaugeValue, float64(stats.Memory.BytesTotal))
+	ch <- prometheus.MustNewConstMetric(c.memoryUsed, prometheus.GaugeValue, float64(stats.Memory.BytesUsed))
+	return nil
+}
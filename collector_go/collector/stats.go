@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ServerMetricsResponse mirrors the JSON body returned by the backend's
+// /stats endpoint.
+type ServerMetricsResponse struct {
+	CPU struct {
+		Load1m  float64 `json:"load_1m"`
+		Load5m  float64 `json:"load_5m"`
+		Load15m float64 `json:"load_15m"`
+	} `json:"cpu"`
+
+	Memory struct {
+		BytesTotal int64 `json:"total_bytes"`
+		BytesUsed  int64 `json:"used_bytes"`
+	} `json:"memory"`
+}
+
+// fetchStats scrapes serverURL's /stats endpoint using client, bound to
+// ctx so a cancelled scrape aborts the request instead of leaking a
+// goroutine. Collectors call this through Config.fetchStats rather than
+// directly, so a scrape needing /stats from more than one collector only
+// hits the backend once.
+func fetchStats(ctx context.Context, client *http.Client, serverURL string) (*ServerMetricsResponse, error) {
+	const endpoint = "/stats"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverURL+endpoint, nil)
+	if err != nil {
+		return nil, scrapeFailure("request_build", serverURL, endpoint, 0, fmt.Errorf("could not build stats request: %w", err))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, scrapeFailure("transport", serverURL, endpoint, 0, fmt.Errorf("could not scrape stats: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, scrapeFailure("http_status", serverURL, endpoint, resp.StatusCode, fmt.Errorf("unexpected status from stats endpoint: %s", resp.Status))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scrapeFailure("body_read", serverURL, endpoint, resp.StatusCode, fmt.Errorf("could not read stats response: %w", err))
+	}
+
+	stats := &ServerMetricsResponse{}
+	if err := json.Unmarshal(body, stats); err != nil {
+		return nil, scrapeFailure("json_decode", serverURL, endpoint, resp.StatusCode, fmt.Errorf("could not decode stats response: %w", err))
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,81 @@
+// Package config loads the exporter's target definitions from a YAML file,
+// replacing the hardcoded serverURL/namespace constants the exporter
+// started with.
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TargetConfig describes one backend server the exporter knows how to
+// scrape, selected at probe time via its Name.
+type TargetConfig struct {
+	Name        string            `yaml:"name"`
+	URL         string            `yaml:"url"`
+	Namespace   string            `yaml:"namespace"`
+	Timeout     time.Duration     `yaml:"timeout"`
+	TLS         TLSConfig         `yaml:"tls"`
+	BasicAuth   *BasicAuthConfig  `yaml:"basic_auth,omitempty"`
+	BearerToken string            `yaml:"bearer_token,omitempty"`
+	Headers     map[string]string `yaml:"headers,omitempty"`
+
+	// ScrapeInterval, if set, polls this target's backend in the
+	// background on that interval and serves /probe from the cached
+	// snapshot instead of scraping synchronously per request. Overrides
+	// the exporter-wide --scrape.interval default.
+	ScrapeInterval time.Duration `yaml:"scrape_interval,omitempty"`
+}
+
+// TLSConfig controls how the exporter's HTTP client verifies itself and the
+// server when scraping a TLS-protected target.
+type TLSConfig struct {
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+}
+
+// BasicAuthConfig holds HTTP basic-auth credentials for a target.
+type BasicAuthConfig struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// Config is the top-level shape of --config.file.
+type Config struct {
+	Targets []TargetConfig `yaml:"targets"`
+}
+
+// Load reads and parses path into a Config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Target looks up a configured target by name.
+func (c *Config) Target(name string) (TargetConfig, bool) {
+	if c == nil {
+		return TargetConfig{}, false
+	}
+
+	for _, t := range c.Targets {
+		if t.Name == name {
+			return t, true
+		}
+	}
+
+	return TargetConfig{}, false
+}
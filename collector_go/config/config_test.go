@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+targets:
+  - name: web1
+    url: http://web1:8080
+    namespace: web1
+    timeout: 5s
+    scrape_interval: 30s
+    basic_auth:
+      username: alice
+      password: secret
+  - name: web2
+    url: http://web2:8080
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+
+	if len(cfg.Targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(cfg.Targets))
+	}
+
+	web1, ok := cfg.Target("web1")
+	if !ok {
+		t.Fatal("web1 not found")
+	}
+	if web1.URL != "http://web1:8080" || web1.Namespace != "web1" {
+		t.Errorf("web1 = %+v, want url/namespace to match config", web1)
+	}
+	if web1.Timeout != 5*time.Second {
+		t.Errorf("web1.Timeout = %s, want 5s", web1.Timeout)
+	}
+	if web1.ScrapeInterval != 30*time.Second {
+		t.Errorf("web1.ScrapeInterval = %s, want 30s", web1.ScrapeInterval)
+	}
+	if web1.BasicAuth == nil || web1.BasicAuth.Username != "alice" {
+		t.Errorf("web1.BasicAuth = %+v, want username alice", web1.BasicAuth)
+	}
+
+	if _, ok := cfg.Target("missing"); ok {
+		t.Error("Target(missing) = true, want false")
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "does-not-exist.yaml")); err == nil {
+		t.Fatal("Load of a missing file returned no error")
+	}
+}
+
+func TestLoadInvalidYAML(t *testing.T) {
+	path := writeConfig(t, "targets: [this is not valid yaml")
+
+	if _, err := Load(path); err == nil {
+		t.Fatal("Load of invalid YAML returned no error")
+	}
+}
+
+func TestConfigTargetOnNilConfig(t *testing.T) {
+	var cfg *Config
+	if _, ok := cfg.Target("anything"); ok {
+		t.Error("Target on a nil Config = true, want false")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chamilad/demo_prometheus_custom_exporters/collector_go/config"
+)
+
+// writeSelfSignedCert writes a self-signed cert/key PEM pair to dir and
+// returns their paths, for exercising buildTLSConfig's CA and client-cert
+// loading without depending on an external CA.
+func writeSelfSignedCert(t *testing.T, dir, name string) (certPath, keyPath string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %s", err)
+	}
+
+	certPath = filepath.Join(dir, name+"-cert.pem")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		t.Fatalf("WriteFile cert: %s", err)
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %s", err)
+	}
+
+	keyPath = filepath.Join(dir, name+"-key.pem")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0o600); err != nil {
+		t.Fatalf("WriteFile key: %s", err)
+	}
+
+	return certPath, keyPath
+}
+
+func TestBuildTLSConfigDefault(t *testing.T) {
+	tlsConfig, err := buildTLSConfig(config.TLSConfig{})
+	if err != nil {
+		t.Fatalf("buildTLSConfig: %s", err)
+	}
+
+	if tlsConfig.InsecureSkipVerify {
+		t.Error("InsecureSkipVerify = true, want false for a zero-value TLSConfig")
+	}
+	if tlsConfig.RootCAs != nil {
+		t.Error("RootCAs set, want nil when no CAFile is given")
+	}
+	if len(tlsConfig.Certificates) != 0 {
+		t.Error("Certificates set, want none when no CertFile/KeyFile is given")
+	}
+}
+
+func TestBuildTLSConfigCAAndClientCert(t *testing.T) {
+	dir := t.TempDir()
+	caCert, _ := writeSelfSignedCert(t, dir, "ca")
+	clientCert, clientKey := writeSelfSignedCert(t, dir, "client")
+
+	tlsConfig, err := buildTLSConfig(config.TLSConfig{
+		InsecureSkipVerify: true,
+		CAFile:             caCert,
+		CertFile:           clientCert,
+		KeyFile:            clientKey,
+	})
+	if err != nil {
+		t.Fatalf("buildTLSConfig: %s", err)
+	}
+
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("InsecureSkipVerify = false, want true")
+	}
+	if tlsConfig.RootCAs == nil {
+		t.Error("RootCAs = nil, want a pool built from CAFile")
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("got %d certificates, want 1", len(tlsConfig.Certificates))
+	}
+}
+
+func TestBuildTLSConfigBadCAFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a cert"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	if _, err := buildTLSConfig(config.TLSConfig{CAFile: path}); err == nil {
+		t.Fatal("buildTLSConfig with a non-PEM CAFile returned no error")
+	}
+}
+
+func TestBuildTLSConfigMissingCAFile(t *testing.T) {
+	if _, err := buildTLSConfig(config.TLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Fatal("buildTLSConfig with a missing CAFile returned no error")
+	}
+}
@@ -1,26 +1,278 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"github.com/chamilad/demo_prometheus_custom_exporters/collector_go/collector"
+	"github.com/chamilad/demo_prometheus_custom_exporters/collector_go/config"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-const (
-	exporterPort      = "9001"
-	exporterNamespace = "my_server_go"
-	serverURL         = "http://127.0.0.1:8443"
+const defaultNamespace = "my_server_go"
+
+var (
+	configFile     = flag.String("config.file", "", "Path to a YAML file defining scrape targets (see config.Config). If unset, \"target\" query parameters are treated as raw backend URLs.")
+	listenAddress  = flag.String("web.listen-address", ":9001", "Address to listen on for web interface and telemetry.")
+	telemetryPath  = flag.String("web.telemetry-path", "/metrics", "Path under which to expose exporter self-metrics.")
+	logLevel       = flag.String("log.level", "info", "Minimum log level to emit: debug, info, warn, or error.")
+	scrapeInterval = flag.Duration("scrape.interval", 0, "If set above zero, poll each target's backend in the background on this interval and serve /probe from the cached snapshot instead of scraping it synchronously per request. A target's scrape_interval in --config.file overrides this default.")
+	currentConfig  atomic.Pointer[config.Config]
+)
+
+// defaultClient is the *http.Client shared by every "target" value that
+// isn't defined in --config.file; they all resolve to a TargetConfig with no
+// timeout, TLS or auth settings of its own, so there's nothing
+// target-specific to give them separate clients for.
+var (
+	defaultClientOnce sync.Once
+	defaultClient     *http.Client
+	defaultClientErr  error
 )
 
+// namedClients holds one *http.Client per --config.file target, keyed by
+// name, so a target's TCP connections and TLS sessions are reused across
+// probes instead of rebuilt on every request. Cleared wholesale on config
+// reload since a target's auth/TLS/timeout may have changed underneath its
+// name.
+var (
+	namedClientsMu sync.Mutex
+	namedClients   = map[string]*http.Client{}
+)
+
+// clientFor returns the shared *http.Client for t, building it on first use.
+func clientFor(t config.TargetConfig, configured bool) (*http.Client, error) {
+	if !configured {
+		defaultClientOnce.Do(func() {
+			defaultClient, defaultClientErr = newHTTPClient(t)
+		})
+		return defaultClient, defaultClientErr
+	}
+
+	namedClientsMu.Lock()
+	defer namedClientsMu.Unlock()
+
+	if c, ok := namedClients[t.Name]; ok {
+		return c, nil
+	}
+
+	c, err := newHTTPClient(t)
+	if err != nil {
+		return nil, err
+	}
+	namedClients[t.Name] = c
+	return c, nil
+}
+
+// resetNamedClients drops every cached named-target client so the next
+// probe of each rebuilds one from the newly-loaded config.
+func resetNamedClients() {
+	namedClientsMu.Lock()
+	namedClients = map[string]*http.Client{}
+	namedClientsMu.Unlock()
+}
+
+// Self-metrics for the exporter process itself, exposed on --web.telemetry-path.
+// Per-target metrics are served on demand via /probe.
+var (
+	probeRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "probe_requests_total",
+			Help: "number of /probe requests handled, by target",
+		},
+		[]string{"target"},
+	)
+
+	probeDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "probe_duration_seconds",
+			Help: "time taken to scrape a target via /probe",
+		},
+		[]string{"target"},
+	)
+
+	probeErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "probe_errors_total",
+			Help: "number of /probe requests that failed before a target could be scraped",
+		},
+		[]string{"target"},
+	)
+
+	probeUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "probe_up",
+			Help: "whether the last /probe of a target was served (1) or rejected (0)",
+		},
+		[]string{"target"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(probeRequestsTotal, probeDurationSeconds, probeErrorsTotal, probeUp)
+}
+
+// resolveTarget turns the "target" query parameter into a collector.Config.
+// If a config file is loaded and defines a target of that name, its URL,
+// namespace, timeout, TLS and auth settings are used; otherwise the
+// parameter is treated as a raw backend URL scraped under defaultNamespace
+// with the exporter's default client settings, preserving the exporter's
+// original no-config behaviour. ctx should come from the incoming scrape
+// request so a cancelled scrape aborts the backend request too.
+func resolveTarget(ctx context.Context, name string) (collector.Config, error) {
+	t := config.TargetConfig{Namespace: defaultNamespace, URL: name}
+	configured := false
+	if cfg := currentConfig.Load(); cfg != nil {
+		if found, ok := cfg.Target(name); ok {
+			t = found
+			configured = true
+			if t.Namespace == "" {
+				t.Namespace = defaultNamespace
+			}
+		}
+	}
+
+	client, err := clientFor(t, configured)
+	if err != nil {
+		return collector.Config{}, fmt.Errorf("could not build HTTP client for target %s: %w", name, err)
+	}
+
+	// Background caching is only offered to targets named in --config.file:
+	// that set is small and operator-controlled, whereas a raw "target"
+	// query parameter could be any string, and caching it would spawn an
+	// unbounded, never-evicted background poller per distinct value seen.
+	var cacheInterval time.Duration
+	if configured {
+		cacheInterval = t.ScrapeInterval
+		if cacheInterval == 0 {
+			cacheInterval = *scrapeInterval
+		}
+	}
+
+	return collector.Config{
+		Namespace:     t.Namespace,
+		ServerURL:     t.URL,
+		Client:        client,
+		Context:       ctx,
+		CacheInterval: cacheInterval,
+	}, nil
+}
+
+// probeHandler implements Prometheus's blackbox-style multi-target pattern:
+// the target to scrape is chosen per-request via the "target" query
+// parameter, so one exporter process can front an entire fleet of backend
+// servers that Prometheus discovers and relabels into probe requests.
+func probeHandler(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		probeErrorsTotal.WithLabelValues(target).Inc()
+		http.Error(w, "target parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	probeRequestsTotal.WithLabelValues(target).Inc()
+
+	targetConfig, err := resolveTarget(r.Context(), target)
+	if err != nil {
+		probeErrorsTotal.WithLabelValues(target).Inc()
+		probeUp.WithLabelValues(target).Set(0)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	nodeCollector, err := collector.NewNodeCollector(targetConfig)
+	if err != nil {
+		probeErrorsTotal.WithLabelValues(target).Inc()
+		probeUp.WithLabelValues(target).Set(0)
+		http.Error(w, fmt.Sprintf("could not build collectors for target: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(nodeCollector)
+
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+
+	probeDurationSeconds.WithLabelValues(target).Observe(time.Since(start).Seconds())
+	probeUp.WithLabelValues(target).Set(1)
+}
+
+// loadConfig reads *configFile, if set, and swaps it into currentConfig.
+// Probe requests in flight keep using the config pointer they already
+// loaded, so this never disrupts an in-progress scrape.
+func loadConfig() error {
+	if *configFile == "" {
+		return nil
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		return err
+	}
+
+	currentConfig.Store(cfg)
+	resetNamedClients()
+	collector.ReconcileCaches(activeTargetURLs(cfg))
+	slog.Info("loaded config", "file", *configFile, "targets", len(cfg.Targets))
+	return nil
+}
+
+// activeTargetURLs returns the set of backend URLs cfg's targets scrape, so
+// ReconcileCaches can tell which background pollers are still wanted.
+func activeTargetURLs(cfg *config.Config) map[string]struct{} {
+	active := make(map[string]struct{}, len(cfg.Targets))
+	for _, t := range cfg.Targets {
+		active[t.URL] = struct{}{}
+	}
+	return active
+}
+
+// watchForReload reloads the config file on SIGHUP so operators can roll out
+// new targets without restarting the listener.
+func watchForReload() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	for range sighup {
+		if err := loadConfig(); err != nil {
+			slog.Error("config reload failed, keeping previous config", "file", *configFile, "err", err)
+		}
+	}
+}
+
+func setLogLevel(level string) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		log.Fatalf("invalid --log.level %q: %s", level, err)
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: l})))
+}
+
 func main() {
-	serverCollector := NewCollector(exporterNamespace, serverURL)
-	prometheus.MustRegister(serverCollector)
+	flag.Parse()
+	setLogLevel(*logLevel)
+
+	if err := loadConfig(); err != nil {
+		log.Fatalf("could not load --config.file: %s", err)
+	}
+	go watchForReload()
+
+	http.HandleFunc("/probe", probeHandler)
+	http.Handle(*telemetryPath, promhttp.Handler())
 
-	// let the client handle the metrics retrieval call
-	http.Handle("/", promhttp.Handler())
-	log.Printf("starting metrics server at %s", exporterPort)
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", exporterPort), nil))
+	slog.Info("starting exporter", "address", *listenAddress)
+	log.Fatal(http.ListenAndServe(*listenAddress, nil))
 }